@@ -0,0 +1,199 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Hub marks a type as the conversion hub for its group/kind: every other
+// version converts to/from it instead of directly to/from each other.
+// Analogous to sigs.k8s.io/controller-runtime/pkg/conversion.Hub.
+type Hub interface {
+	runtime.Object
+	Hub()
+}
+
+// Convertible is implemented by every non-hub version of a group/kind that
+// ConvertViaHub should support. Analogous to
+// sigs.k8s.io/controller-runtime/pkg/conversion.Convertible.
+type Convertible interface {
+	runtime.Object
+	ConvertTo(dst Hub) error
+	ConvertFrom(src Hub) error
+}
+
+// ConversionWebhook serves apiextensions.k8s.io/v1 CRD conversion requests.
+type ConversionWebhook struct {
+	// Scheme decodes each object carried by the request and, for the
+	// default ConvertFunc, allocates a fresh instance of the desired
+	// apiVersion/kind. Required; if unset, it is set automatically to the
+	// owning Server's Scheme when registered via Server.RegisterConversion.
+	Scheme *runtime.Scheme
+
+	// ConvertFunc converts from to an instance of toVersion (e.g.
+	// "example.com/v2"). Defaults to ConvertViaHub(Scheme), which requires
+	// from's Go type and the type registered for toVersion to each be
+	// either the Hub or Convertible via a shared Hub.
+	ConvertFunc func(ctx context.Context, from runtime.Object, toVersion string) (runtime.Object, error)
+}
+
+var _ http.Handler = &ConversionWebhook{}
+
+// ServeHTTP implements http.Handler.
+func (c *ConversionWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	review := &apiextensionsv1.ConversionReview{}
+	if err := json.NewDecoder(r.Body).Decode(review); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	review.Response = c.convert(r.Context(), review.Request)
+	review.Request = nil
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		logf.Log.WithName("conversion-webhook").Error(err, "unable to encode conversion response")
+	}
+}
+
+func (c *ConversionWebhook) convert(ctx context.Context, req *apiextensionsv1.ConversionRequest) *apiextensionsv1.ConversionResponse {
+	if req == nil {
+		return convertErrored(fmt.Errorf("conversion request is nil"))
+	}
+
+	convertFunc := c.ConvertFunc
+	if convertFunc == nil {
+		convertFunc = ConvertViaHub(c.Scheme)
+	}
+
+	decoder := serializer.NewCodecFactory(c.Scheme).UniversalDeserializer()
+
+	converted := make([]runtime.RawExtension, 0, len(req.Objects))
+	for _, in := range req.Objects {
+		obj, _, err := decoder.Decode(in.Raw, nil, nil)
+		if err != nil {
+			return convertErrored(err)
+		}
+		out, err := convertFunc(ctx, obj, req.DesiredAPIVersion)
+		if err != nil {
+			return convertErrored(err)
+		}
+		converted = append(converted, runtime.RawExtension{Object: out})
+	}
+
+	return &apiextensionsv1.ConversionResponse{
+		UID:              req.UID,
+		ConvertedObjects: converted,
+		Result:           metav1.Status{Status: metav1.StatusSuccess},
+	}
+}
+
+func convertErrored(err error) *apiextensionsv1.ConversionResponse {
+	return &apiextensionsv1.ConversionResponse{
+		Result: metav1.Status{Status: metav1.StatusFailure, Message: err.Error()},
+	}
+}
+
+// ConvertViaHub returns a ConvertFunc that converts from to toVersion by
+// routing through the Hub registered for their shared group/kind in scheme:
+// from converts to the Hub (or already is it), and the Hub converts to a
+// fresh instance of toVersion. It is ConversionWebhook's default ConvertFunc.
+func ConvertViaHub(scheme *runtime.Scheme) func(ctx context.Context, from runtime.Object, toVersion string) (runtime.Object, error) {
+	return func(ctx context.Context, from runtime.Object, toVersion string) (runtime.Object, error) {
+		fromGVK := from.GetObjectKind().GroupVersionKind()
+		toGVK := schema.FromAPIVersionAndKind(toVersion, fromGVK.Kind)
+
+		to, err := scheme.New(toGVK)
+		if err != nil {
+			return nil, fmt.Errorf("webhook: allocating %s: %w", toGVK, err)
+		}
+		to.GetObjectKind().SetGroupVersionKind(toGVK)
+
+		if err := convertViaHub(scheme, from, to); err != nil {
+			return nil, err
+		}
+		return to, nil
+	}
+}
+
+// convertViaHub converts src into dst in place, following the same
+// src/dst-kind rules as controller-runtime's conversion webhook: either side
+// may already be the Hub, or both may be Convertible spokes that round-trip
+// through the Hub registered for their group/kind.
+func convertViaHub(scheme *runtime.Scheme, src, dst runtime.Object) error {
+	srcGVK := src.GetObjectKind().GroupVersionKind()
+	dstGVK := dst.GetObjectKind().GroupVersionKind()
+	if srcGVK.GroupKind() != dstGVK.GroupKind() {
+		return fmt.Errorf("webhook: %T and %T do not belong to the same group/kind", src, dst)
+	}
+
+	switch {
+	case isHub(src):
+		dstConvertible, ok := dst.(Convertible)
+		if !ok {
+			return fmt.Errorf("webhook: %T is not Convertible", dst)
+		}
+		return dstConvertible.ConvertFrom(src.(Hub))
+	case isHub(dst):
+		srcConvertible, ok := src.(Convertible)
+		if !ok {
+			return fmt.Errorf("webhook: %T is not Convertible", src)
+		}
+		return srcConvertible.ConvertTo(dst.(Hub))
+	default:
+		srcConvertible, srcOK := src.(Convertible)
+		dstConvertible, dstOK := dst.(Convertible)
+		if !srcOK || !dstOK {
+			return fmt.Errorf("webhook: conversion between %T and %T requires a shared Hub", src, dst)
+		}
+		hub, err := hubFor(scheme, srcGVK.GroupKind())
+		if err != nil {
+			return err
+		}
+		if err := srcConvertible.ConvertTo(hub); err != nil {
+			return fmt.Errorf("webhook: converting %T to hub version %T: %w", src, hub, err)
+		}
+		if err := dstConvertible.ConvertFrom(hub); err != nil {
+			return fmt.Errorf("webhook: converting %T from hub version %T: %w", dst, hub, err)
+		}
+		return nil
+	}
+}
+
+// hubFor returns a fresh instance of the Hub registered for groupKind.
+func hubFor(scheme *runtime.Scheme, groupKind schema.GroupKind) (Hub, error) {
+	var hub Hub
+	for gvk := range scheme.AllKnownTypes() {
+		if gvk.GroupKind() != groupKind {
+			continue
+		}
+		instance, err := scheme.New(gvk)
+		if err != nil {
+			return nil, fmt.Errorf("webhook: allocating %s: %w", gvk, err)
+		}
+		if h, ok := instance.(Hub); ok {
+			if hub != nil {
+				return nil, fmt.Errorf("webhook: multiple hub versions registered for %s", groupKind)
+			}
+			hub = h
+		}
+	}
+	if hub == nil {
+		return nil, fmt.Errorf("webhook: no hub version registered for %s", groupKind)
+	}
+	return hub, nil
+}
+
+func isHub(obj runtime.Object) bool {
+	_, ok := obj.(Hub)
+	return ok
+}
@@ -0,0 +1,157 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// widgetV1 is the conversion hub for the test "widgets.example.com" kind.
+type widgetV1 struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Data              string `json:"data,omitempty"`
+}
+
+func (w *widgetV1) Hub()                           {}
+func (w *widgetV1) DeepCopyObject() runtime.Object { c := *w; return &c }
+
+// widgetV2 is a spoke version that round-trips through widgetV1.
+type widgetV2 struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	DataV2            string `json:"dataV2,omitempty"`
+}
+
+func (w *widgetV2) DeepCopyObject() runtime.Object { c := *w; return &c }
+
+func (w *widgetV2) ConvertTo(dst Hub) error {
+	hub := dst.(*widgetV1)
+	hub.ObjectMeta = w.ObjectMeta
+	hub.Data = w.DataV2
+	return nil
+}
+
+func (w *widgetV2) ConvertFrom(src Hub) error {
+	hub := src.(*widgetV1)
+	w.ObjectMeta = hub.ObjectMeta
+	w.DataV2 = hub.Data
+	return nil
+}
+
+// widgetV3 is neither a Hub nor Convertible, to exercise the error path.
+type widgetV3 struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+}
+
+func (w *widgetV3) DeepCopyObject() runtime.Object { c := *w; return &c }
+
+func widgetScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	gv1 := schema.GroupVersion{Group: "example.com", Version: "v1"}
+	gv2 := schema.GroupVersion{Group: "example.com", Version: "v2"}
+	scheme.AddKnownTypeWithName(gv1.WithKind("Widget"), &widgetV1{})
+	scheme.AddKnownTypeWithName(gv2.WithKind("Widget"), &widgetV2{})
+	return scheme
+}
+
+var _ = Describe("ConvertViaHub", func() {
+	It("converts a spoke to the hub version's Go type", func() {
+		scheme := widgetScheme()
+		from := &widgetV2{DataV2: "hello"}
+		from.SetGroupVersionKind(schema.GroupVersionKind{Group: "example.com", Version: "v2", Kind: "Widget"})
+
+		out, err := ConvertViaHub(scheme)(context.TODO(), from, "example.com/v1")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(out.(*widgetV1).Data).Should(Equal("hello"))
+	})
+
+	It("errors when the destination version isn't Convertible", func() {
+		scheme := runtime.NewScheme()
+		gv1 := schema.GroupVersion{Group: "example.com", Version: "v1"}
+		scheme.AddKnownTypeWithName(gv1.WithKind("Widget"), &widgetV1{})
+		scheme.AddKnownTypeWithName(schema.GroupVersion{Group: "example.com", Version: "v3"}.WithKind("Widget"), &widgetV3{})
+
+		from := &widgetV1{Data: "hello"}
+		from.SetGroupVersionKind(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"})
+
+		_, err := ConvertViaHub(scheme)(context.TODO(), from, "example.com/v3")
+		Ω(err).Should(HaveOccurred())
+	})
+})
+
+var _ = Describe("ConversionWebhook", func() {
+	It("serves a ConversionReview using the default hub-based ConvertFunc", func() {
+		scheme := widgetScheme()
+		cw := &ConversionWebhook{Scheme: scheme}
+
+		raw, err := json.Marshal(&widgetV2{
+			TypeMeta: metav1.TypeMeta{APIVersion: "example.com/v2", Kind: "Widget"},
+			DataV2:   "hello",
+		})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		review := apiextensionsv1.ConversionReview{
+			Request: &apiextensionsv1.ConversionRequest{
+				UID:               "the-uid",
+				DesiredAPIVersion: "example.com/v1",
+				Objects:           []runtime.RawExtension{{Raw: raw}},
+			},
+		}
+		body, err := json.Marshal(review)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		req := httptest.NewRequest("POST", "/convert", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		cw.ServeHTTP(w, req)
+
+		var reply apiextensionsv1.ConversionReview
+		Ω(json.Unmarshal(w.Body.Bytes(), &reply)).Should(Succeed())
+		Ω(reply.Response.Result.Status).Should(Equal(metav1.StatusSuccess))
+		Ω(reply.Response.ConvertedObjects).Should(HaveLen(1))
+	})
+
+	It("reports conversion errors via the response's Result, not an HTTP error", func() {
+		cw := &ConversionWebhook{
+			Scheme: widgetScheme(),
+			ConvertFunc: func(ctx context.Context, from runtime.Object, toVersion string) (runtime.Object, error) {
+				return nil, errBoom
+			},
+		}
+
+		raw, err := json.Marshal(&widgetV1{TypeMeta: metav1.TypeMeta{APIVersion: "example.com/v1", Kind: "Widget"}})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		review := apiextensionsv1.ConversionReview{
+			Request: &apiextensionsv1.ConversionRequest{
+				UID:               "the-uid",
+				DesiredAPIVersion: "example.com/v2",
+				Objects:           []runtime.RawExtension{{Raw: raw}},
+			},
+		}
+		body, err := json.Marshal(review)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		req := httptest.NewRequest("POST", "/convert", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		cw.ServeHTTP(w, req)
+
+		var reply apiextensionsv1.ConversionReview
+		Ω(json.Unmarshal(w.Body.Bytes(), &reply)).Should(Succeed())
+		Ω(reply.Response.Result.Status).Should(Equal(metav1.StatusFailure))
+		Ω(reply.Response.Result.Message).Should(ContainSubstring("boom"))
+	})
+})
+
+var errBoom = fmt.Errorf("boom")
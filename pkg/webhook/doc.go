@@ -0,0 +1,5 @@
+// Package webhook provides a generic admission.Handler that decodes the
+// object carried by an admission.Request into a caller-supplied type before
+// delegating to a user-provided Handler, so that individual webhooks don't
+// each have to repeat the same decode boilerplate.
+package webhook
@@ -0,0 +1,115 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// handler adapts a user-supplied admission.Handler so that it never has to
+// deal with raw JSON itself: when Object is set, the request's Object and
+// OldObject are decoded into a fresh instance of Object before the wrapped
+// Handler is invoked.
+type handler struct {
+	// Handler is invoked with the decoded request. A nil Handler denies the
+	// request, matching the fail-closed default of the admission webhook
+	// contract.
+	Handler admission.Handler
+
+	// Object is a zero-value instance of the type the incoming
+	// AdmissionRequest's Object/OldObject should be decoded into. If nil, no
+	// decoding is performed and Handler sees the raw request as-is.
+	Object runtime.Object
+
+	// LogConstructor builds the logger that is injected into the context
+	// passed to Handler.Handle, so that Validate/Mutate funcs can retrieve a
+	// logger pre-populated with request metadata via logf.FromContext(ctx)
+	// instead of threading it through themselves. Defaults to
+	// DefaultLogConstructor.
+	LogConstructor func(base logr.Logger, req admission.Request) logr.Logger
+
+	// Observer, if set, is notified once Handle returns with the request's
+	// GVK/operation/decision and total latency. Defaults to a no-op.
+	Observer Observer
+
+	decoder *admission.Decoder
+}
+
+// Handle implements admission.Handler.
+func (h *handler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	logConstructor := h.LogConstructor
+	if logConstructor == nil {
+		logConstructor = DefaultLogConstructor
+	}
+	ctx = logf.IntoContext(ctx, logConstructor(logf.FromContext(ctx), req))
+
+	observer := h.Observer
+	if observer == nil {
+		observer = defaultObserver
+	}
+
+	start := time.Now()
+	resp := h.handle(ctx, req)
+	observer.ObserveRequest(req.Kind, req.Resource, req.Operation, resp.Allowed, time.Since(start))
+	return resp
+}
+
+// handle decodes req.Object/OldObject when Object is set and delegates to
+// Handler, tracing both steps.
+func (h *handler) handle(ctx context.Context, req admission.Request) admission.Response {
+	if h.Handler == nil {
+		return admission.Denied("no handler configured")
+	}
+
+	if h.Object != nil && h.decoder != nil {
+		_, span := tracer.Start(ctx, "webhook.decode")
+		if len(req.Object.Raw) > 0 {
+			obj := h.Object.DeepCopyObject()
+			if err := h.decoder.DecodeRaw(req.Object, obj); err != nil {
+				span.RecordError(err)
+				span.End()
+				return admission.Errored(http.StatusBadRequest, err)
+			}
+			req.Object.Object = obj
+		}
+		if len(req.OldObject.Raw) > 0 {
+			oldObj := h.Object.DeepCopyObject()
+			if err := h.decoder.DecodeRaw(req.OldObject, oldObj); err != nil {
+				span.RecordError(err)
+				span.End()
+				return admission.Errored(http.StatusBadRequest, err)
+			}
+			req.OldObject.Object = oldObj
+		}
+		span.End()
+	}
+
+	handleCtx, span := tracer.Start(ctx, "webhook.handle")
+	defer span.End()
+	return h.Handler.Handle(handleCtx, req)
+}
+
+// InjectDecoder implements admission.DecoderInjector.
+func (h *handler) InjectDecoder(d *admission.Decoder) error {
+	h.decoder = d
+	if _, err := admission.InjectDecoderInto(d, h.Handler); err != nil {
+		return err
+	}
+	return nil
+}
+
+// InjectClient implements inject.Client.
+func (h *handler) InjectClient(c client.Client) error {
+	if _, err := inject.ClientInto(c, h.Handler); err != nil {
+		return err
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 
+	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -13,6 +14,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
@@ -168,6 +170,48 @@ var _ = Describe("Handler", func() {
 			Ω(result.Allowed).Should(BeFalse())
 		})
 	})
+	Context("LogConstructor", func() {
+		It("should inject a logger reachable via logf.FromContext by default", func() {
+			var gotLogger logr.Logger
+			h := handler{
+				Handler: &MutateFunc{
+					Func: func(ctx context.Context, request admission.Request) admission.Response {
+						gotLogger = logf.FromContext(ctx)
+						return admission.Allowed("")
+					},
+				},
+			}
+
+			result := h.Handle(context.TODO(), admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					UID: "some-uid",
+				},
+			})
+			Ω(result.Allowed).Should(BeTrue())
+			Ω(gotLogger.GetSink()).ShouldNot(BeNil())
+		})
+		It("should use the configured LogConstructor instead of the default", func() {
+			var gotLogger logr.Logger
+			called := false
+			h := handler{
+				LogConstructor: func(base logr.Logger, req admission.Request) logr.Logger {
+					called = true
+					return base.WithValues("custom", true)
+				},
+				Handler: &MutateFunc{
+					Func: func(ctx context.Context, request admission.Request) admission.Response {
+						gotLogger = logf.FromContext(ctx)
+						return admission.Allowed("")
+					},
+				},
+			}
+
+			result := h.Handle(context.TODO(), admission.Request{})
+			Ω(result.Allowed).Should(BeTrue())
+			Ω(called).Should(BeTrue())
+			Ω(gotLogger.GetSink()).ShouldNot(BeNil())
+		})
+	})
 	Context("InjectDecoder", func() {
 		var (
 			decoder *admission.Decoder
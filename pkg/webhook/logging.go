@@ -0,0 +1,23 @@
+package webhook
+
+import (
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// DefaultLogConstructor adds the admission request's identifying fields to
+// base: its UID, the kind and resource being admitted, the namespace/name of
+// the object, the operation, and the requesting user. It is used whenever a
+// LogConstructor is not supplied, and callers may use it as a starting point
+// for their own.
+func DefaultLogConstructor(base logr.Logger, req admission.Request) logr.Logger {
+	return base.WithValues(
+		"webhookRequestUID", req.UID,
+		"kind", req.Kind,
+		"resource", req.Resource,
+		"namespace", req.Namespace,
+		"name", req.Name,
+		"operation", req.Operation,
+		"user", req.UserInfo.Username,
+	)
+}
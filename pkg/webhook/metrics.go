@@ -0,0 +1,62 @@
+package webhook
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Observer is notified once per admission request handled by handler,
+// ValidatingWebhook or MutatingWebhook, after the wrapped Handler has
+// returned. Implementations must be safe for concurrent use.
+type Observer interface {
+	ObserveRequest(kind metav1.GroupVersionKind, resource metav1.GroupVersionResource, operation admissionv1.Operation, allowed bool, duration time.Duration)
+}
+
+// NoopObserver discards every observation. It is the default Observer when
+// none is configured.
+type NoopObserver struct{}
+
+// ObserveRequest implements Observer.
+func (NoopObserver) ObserveRequest(metav1.GroupVersionKind, metav1.GroupVersionResource, admissionv1.Operation, bool, time.Duration) {
+}
+
+var defaultObserver Observer = NoopObserver{}
+
+// PrometheusObserver is an Observer backed by a request counter keyed by
+// GVK/operation/allowed and a latency histogram keyed by GVK/operation.
+type PrometheusObserver struct {
+	requestsTotal   *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+}
+
+// NewPrometheusObserver creates a PrometheusObserver and registers its
+// collectors with registerer.
+func NewPrometheusObserver(registerer prometheus.Registerer) (*PrometheusObserver, error) {
+	o := &PrometheusObserver{
+		requestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "webhook_admission_requests_total",
+			Help: "Total number of admission requests handled, by GVK, operation and decision.",
+		}, []string{"group", "version", "kind", "operation", "allowed"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "webhook_admission_request_duration_seconds",
+			Help:    "Latency of admission requests, by GVK and operation.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"group", "version", "kind", "operation"}),
+	}
+	for _, c := range []prometheus.Collector{o.requestsTotal, o.requestDuration} {
+		if err := registerer.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+// ObserveRequest implements Observer.
+func (o *PrometheusObserver) ObserveRequest(kind metav1.GroupVersionKind, _ metav1.GroupVersionResource, operation admissionv1.Operation, allowed bool, duration time.Duration) {
+	o.requestsTotal.WithLabelValues(kind.Group, kind.Version, kind.Kind, string(operation), strconv.FormatBool(allowed)).Inc()
+	o.requestDuration.WithLabelValues(kind.Group, kind.Version, kind.Kind, string(operation)).Observe(duration.Seconds())
+}
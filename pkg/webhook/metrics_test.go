@@ -0,0 +1,50 @@
+package webhook
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("PrometheusObserver", func() {
+	It("labels the request counter and latency histogram by GVK, operation and decision", func() {
+		registry := prometheus.NewRegistry()
+		observer, err := NewPrometheusObserver(registry)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		kind := metav1.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+		observer.ObserveRequest(kind, metav1.GroupVersionResource{}, admissionv1.Update, true, 5*time.Millisecond)
+
+		families, err := registry.Gather()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		var counter, histogram *dto.MetricFamily
+		for _, f := range families {
+			switch f.GetName() {
+			case "webhook_admission_requests_total":
+				counter = f
+			case "webhook_admission_request_duration_seconds":
+				histogram = f
+			}
+		}
+		Ω(counter).ShouldNot(BeNil())
+		Ω(counter.Metric).Should(HaveLen(1))
+		Ω(counter.Metric[0].Counter.GetValue()).Should(Equal(1.0))
+
+		Ω(histogram).ShouldNot(BeNil())
+		Ω(histogram.Metric).Should(HaveLen(1))
+		Ω(histogram.Metric[0].Histogram.GetSampleCount()).Should(Equal(uint64(1)))
+	})
+
+	It("discards observations in NoopObserver, the default when no Observer is configured", func() {
+		Ω(func() {
+			NoopObserver{}.ObserveRequest(metav1.GroupVersionKind{}, metav1.GroupVersionResource{}, admissionv1.Create, false, time.Second)
+		}).ShouldNot(Panic())
+	})
+})
@@ -0,0 +1,23 @@
+package webhook
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// MutateFunc is an admission.Handler backed by a single function, for
+// mutating webhooks that don't need to distinguish between operations. Func
+// can retrieve a logger pre-populated with request metadata via
+// logf.FromContext(ctx).
+type MutateFunc struct {
+	Func func(ctx context.Context, req admission.Request) admission.Response
+}
+
+// Handle implements admission.Handler.
+func (f *MutateFunc) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if f.Func == nil {
+		return admission.Allowed("")
+	}
+	return f.Func(ctx, req)
+}
@@ -0,0 +1,141 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// Mutator defaults an object independently of that object's Go type
+// implementing any particular interface, so the same Mutator can be reused
+// across unrelated types.
+type Mutator interface {
+	Default(ctx context.Context, obj runtime.Object) error
+}
+
+// maxReinvocations bounds how many times the mutator chain is re-run under
+// admissionregistrationv1.IfNeededReinvocationPolicy before Handle gives up
+// and reports the chain as non-convergent.
+const maxReinvocations = 10
+
+// MutatingWebhook is an admission.Handler that runs a chain of Mutators
+// against the decoded object carried by the request and returns the result
+// as a single merged JSON patch.
+type MutatingWebhook struct {
+	// Mutators are invoked in order against a copy of the decoded Object for
+	// the operation in question. An empty chain leaves every request
+	// unchanged.
+	Mutators []Mutator
+
+	// ReinvocationPolicy mirrors
+	// admissionregistrationv1.MutatingWebhook.ReinvocationPolicy: with
+	// IfNeededReinvocationPolicy, whenever a pass through the chain changes
+	// the object, the whole chain is re-run against the new object, until a
+	// pass leaves it unchanged or maxReinvocations re-runs have been made,
+	// so that idempotent mutators can converge on a final result within a
+	// single admission evaluation. Any other value, including the zero
+	// value, behaves like NeverReinvocationPolicy and runs the chain once.
+	ReinvocationPolicy admissionregistrationv1.ReinvocationPolicyType
+
+	// Decoder and Client are populated via InjectDecoder/InjectClient and
+	// made available to Mutator implementations that need them.
+	Decoder *admission.Decoder
+	Client  client.Client
+
+	// LogConstructor, if set, further enriches the logger already present
+	// in ctx (see handler.LogConstructor) before the Mutators are invoked.
+	LogConstructor func(base logr.Logger, req admission.Request) logr.Logger
+
+	// Observer, if set, is notified once Handle returns with the request's
+	// GVK/operation/decision and the time spent running the mutator chain.
+	// Defaults to a no-op. Set this when MutatingWebhook is used directly as
+	// an admission.Handler, without going through handler.
+	Observer Observer
+}
+
+// Handle implements admission.Handler.
+func (w *MutatingWebhook) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if w.LogConstructor != nil {
+		ctx = logf.IntoContext(ctx, w.LogConstructor(logf.FromContext(ctx), req))
+	}
+
+	observer := w.Observer
+	if observer == nil {
+		observer = defaultObserver
+	}
+
+	start := time.Now()
+	resp := w.mutate(ctx, req)
+	observer.ObserveRequest(req.Kind, req.Resource, req.Operation, resp.Allowed, time.Since(start))
+	return resp
+}
+
+func (w *MutatingWebhook) mutate(ctx context.Context, req admission.Request) admission.Response {
+	if len(w.Mutators) == 0 || req.Object.Object == nil {
+		return admission.Allowed("")
+	}
+
+	ctx, span := tracer.Start(ctx, "webhook.mutate")
+	defer span.End()
+
+	mutated := req.Object.Object.DeepCopyObject()
+	for round := 0; ; round++ {
+		preImage := mutated.DeepCopyObject()
+		for _, mutator := range w.Mutators {
+			if err := mutator.Default(ctx, mutated); err != nil {
+				span.RecordError(err)
+				return admission.Denied(err.Error())
+			}
+		}
+
+		if apiequality.Semantic.DeepEqual(preImage, mutated) {
+			break
+		}
+		if w.ReinvocationPolicy != admissionregistrationv1.IfNeededReinvocationPolicy {
+			break
+		}
+		if round >= maxReinvocations {
+			err := fmt.Errorf("mutator chain did not converge after %d reinvocations", maxReinvocations)
+			span.RecordError(err)
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+	}
+
+	marshaled, err := json.Marshal(mutated)
+	if err != nil {
+		span.RecordError(err)
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+
+// InjectDecoder implements admission.DecoderInjector.
+func (w *MutatingWebhook) InjectDecoder(d *admission.Decoder) error {
+	w.Decoder = d
+	return nil
+}
+
+// InjectClient implements inject.Client.
+func (w *MutatingWebhook) InjectClient(c client.Client) error {
+	w.Client = c
+	return nil
+}
+
+// PatchType returns a pointer to admissionv1.PatchTypeJSONPatch, for callers
+// that build an admission.Response by hand instead of going through
+// admission.PatchResponseFromRaw.
+func PatchType() *admissionv1.PatchType {
+	patchType := admissionv1.PatchTypeJSONPatch
+	return &patchType
+}
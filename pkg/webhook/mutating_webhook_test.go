@@ -0,0 +1,132 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// mutatorFunc lets a plain function satisfy Mutator.
+type mutatorFunc func(ctx context.Context, obj runtime.Object) error
+
+func (f mutatorFunc) Default(ctx context.Context, obj runtime.Object) error {
+	return f(ctx, obj)
+}
+
+var _ = Describe("MutatingWebhook", func() {
+	var pod *corev1.Pod
+	var raw []byte
+
+	BeforeEach(func() {
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "bar",
+			},
+		}
+		var err error
+		raw, err = json.Marshal(pod)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	handle := func(w *MutatingWebhook) admission.Response {
+		return w.Handle(context.TODO(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Object: runtime.RawExtension{Raw: raw, Object: pod},
+			},
+		})
+	}
+
+	It("allows the request unchanged when there are no mutators", func() {
+		result := handle(&MutatingWebhook{})
+		Ω(result.Allowed).Should(BeTrue())
+		Ω(result.Patches).Should(BeEmpty())
+	})
+
+	It("runs the chain once under NeverReinvocationPolicy, even if an earlier mutator saw a stale object", func() {
+		setA := mutatorFunc(func(ctx context.Context, obj runtime.Object) error {
+			obj.(*corev1.Pod).Labels = map[string]string{"a": "1"}
+			return nil
+		})
+		addBIfA := mutatorFunc(func(ctx context.Context, obj runtime.Object) error {
+			p := obj.(*corev1.Pod)
+			if p.Labels["a"] == "1" {
+				p.Labels["b"] = "1"
+			}
+			return nil
+		})
+
+		result := handle(&MutatingWebhook{Mutators: []Mutator{addBIfA, setA}})
+		Ω(result.Allowed).Should(BeTrue())
+		Ω(result.Patches).ShouldNot(BeEmpty())
+		Ω(result.PatchType).ShouldNot(BeNil())
+		Ω(*result.PatchType).Should(Equal(admissionv1.PatchTypeJSONPatch))
+
+		// addBIfA ran before setA within the single pass, so it never saw
+		// the "a" label and "b" was not added.
+		for _, p := range result.Patches {
+			Ω(p.Path).ShouldNot(ContainSubstring("/b"))
+		}
+	})
+
+	It("re-runs the chain until it converges under IfNeededReinvocationPolicy", func() {
+		setA := mutatorFunc(func(ctx context.Context, obj runtime.Object) error {
+			p := obj.(*corev1.Pod)
+			if p.Labels == nil {
+				p.Labels = map[string]string{}
+			}
+			p.Labels["a"] = "1"
+			return nil
+		})
+		addBIfA := mutatorFunc(func(ctx context.Context, obj runtime.Object) error {
+			p := obj.(*corev1.Pod)
+			if p.Labels["a"] == "1" {
+				p.Labels["b"] = "1"
+			}
+			return nil
+		})
+
+		result := handle(&MutatingWebhook{
+			Mutators:           []Mutator{addBIfA, setA},
+			ReinvocationPolicy: admissionregistrationv1.IfNeededReinvocationPolicy,
+		})
+		Ω(result.Allowed).Should(BeTrue())
+		Ω(result.Patches).ShouldNot(BeEmpty())
+
+		foundB := false
+		for _, p := range result.Patches {
+			if labels, ok := p.Value.(map[string]interface{}); ok && labels["b"] == "1" {
+				foundB = true
+			}
+		}
+		Ω(foundB).Should(BeTrue(), "expected the first reinvocation round to add label b")
+	})
+
+	It("bails out with an error if the chain never stabilizes", func() {
+		flipFlop := mutatorFunc(func(ctx context.Context, obj runtime.Object) error {
+			p := obj.(*corev1.Pod)
+			if p.Labels["flip"] == "true" {
+				p.Labels = map[string]string{"flip": "false"}
+			} else {
+				p.Labels = map[string]string{"flip": "true"}
+			}
+			return nil
+		})
+
+		result := handle(&MutatingWebhook{
+			Mutators:           []Mutator{flipFlop},
+			ReinvocationPolicy: admissionregistrationv1.IfNeededReinvocationPolicy,
+		})
+		Ω(result.Allowed).Should(BeFalse())
+		Ω(result.Result.Message).Should(ContainSubstring("did not converge"))
+	})
+})
@@ -0,0 +1,280 @@
+package webhook
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// Server multiplexes any number of ValidatingWebhook/MutatingWebhook
+// instances behind a single http.Handler, deriving each one's path from its
+// schema.GroupVersionKind the way the controller-runtime builder does (e.g.
+// "/validate-apps-v1-deployment", "/mutate-core-v1-pod"). Unlike
+// controller-runtime's own webhook.Server, it speaks both admission/v1 and
+// admission/v1beta1 AdmissionReview payloads (see DecodeAdmissionReview,
+// EncodeAdmissionReview) and so needs no manager to run standalone.
+type Server struct {
+	// Scheme resolves the GroupVersionKind of the objects passed to For and
+	// is used to construct a fresh instance of a registered GVK for
+	// decoding. Required.
+	Scheme *runtime.Scheme
+
+	// Client, if set, is injected into every registered
+	// ValidatingWebhook/MutatingWebhook, populating its Client field. It is
+	// not passed on to the webhook's Validator or Mutators.
+	Client client.Client
+
+	// Observer, if set, is notified of every request served by a registered
+	// ValidatingWebhook/MutatingWebhook, through the decoding handler that
+	// Register wraps it in; the webhook's own Observer field is left as is.
+	// Defaults to a no-op.
+	Observer Observer
+
+	// Registerer is used to register the per-path request counter.
+	// Defaults to prometheus.DefaultRegisterer.
+	Registerer prometheus.Registerer
+
+	initOnce sync.Once
+	mu       sync.Mutex
+	handlers map[string]http.Handler
+	decoder  *admission.Decoder
+	requests *prometheus.CounterVec
+}
+
+func (s *Server) init() {
+	s.handlers = map[string]http.Handler{}
+
+	registerer := s.Registerer
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+	s.requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "webhook_server_requests_total",
+		Help: "Total number of requests received by Server, by registered path.",
+	}, []string{"path"})
+	registerer.MustRegister(s.requests)
+}
+
+// Register wraps h in a decoding handler for gvk and serves it at the path
+// derived from gvk and h's kind (ValidatingWebhook or MutatingWebhook). It
+// returns an error if gvk isn't known to Scheme or if the derived path is
+// already registered.
+func (s *Server) Register(gvk schema.GroupVersionKind, h admission.Handler) error {
+	s.initOnce.Do(s.init)
+
+	path, err := pathFor(gvk, h)
+	if err != nil {
+		return err
+	}
+
+	obj, err := s.Scheme.New(gvk)
+	if err != nil {
+		return fmt.Errorf("webhook: looking up %s in scheme: %w", gvk, err)
+	}
+
+	decoder, err := s.getDecoder()
+	if err != nil {
+		return err
+	}
+
+	wrapped := &handler{Handler: h, Object: obj, Observer: s.Observer}
+	if err := wrapped.InjectDecoder(decoder); err != nil {
+		return err
+	}
+	if s.Client != nil {
+		if err := wrapped.InjectClient(s.Client); err != nil {
+			return err
+		}
+	}
+
+	return s.register(path, admissionHTTPHandler{wrapped}, gvk)
+}
+
+// RegisterConversion serves cw at path, typically "/convert". If cw.Scheme
+// is unset, it defaults to s.Scheme, so a single Server can host admission
+// and conversion endpoints off of one shared scheme.
+func (s *Server) RegisterConversion(path string, cw *ConversionWebhook) error {
+	s.initOnce.Do(s.init)
+
+	if cw.Scheme == nil {
+		cw.Scheme = s.Scheme
+	}
+
+	return s.register(path, cw, schema.GroupVersionKind{})
+}
+
+// getDecoder lazily builds the admission.Decoder shared by every handler
+// Register wraps, so the scheme is only walked once.
+func (s *Server) getDecoder() (*admission.Decoder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.decoder == nil {
+		decoder, err := admission.NewDecoder(s.Scheme)
+		if err != nil {
+			return nil, err
+		}
+		s.decoder = decoder
+	}
+	return s.decoder, nil
+}
+
+// register records h at path, guarding against registering the same path
+// twice and logging the route the way Register's callers expect.
+func (s *Server) register(path string, h http.Handler, gvk schema.GroupVersionKind) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, found := s.handlers[path]; found {
+		return fmt.Errorf("webhook: path %q is already registered", path)
+	}
+
+	s.handlers[path] = h
+	log := logf.Log.WithName("webhook-server").WithValues("path", path)
+	if gvk != (schema.GroupVersionKind{}) {
+		log = log.WithValues("gvk", gvk)
+	}
+	log.Info("registered webhook")
+	return nil
+}
+
+// ServeHTTP implements http.Handler, dispatching to the webhook registered
+// for r.URL.Path.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	h, found := s.handlers[r.URL.Path]
+	s.mu.Unlock()
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+	s.requests.WithLabelValues(r.URL.Path).Inc()
+	h.ServeHTTP(w, r)
+}
+
+// admissionHTTPHandler adapts an admission.Handler already wrapped for
+// decoding (see handler) into an http.Handler that speaks the
+// admission/v1 and admission/v1beta1 AdmissionReview wire format (see
+// DecodeAdmissionReview, EncodeAdmissionReview).
+type admissionHTTPHandler struct {
+	admission.Handler
+}
+
+func (a admissionHTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	req, gvk, err := DecodeAdmissionReview(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp := a.Handle(r.Context(), req)
+	if err := resp.Complete(req); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := EncodeAdmissionReview(w, resp, gvk); err != nil {
+		logf.Log.WithName("webhook-server").Error(err, "unable to encode admission response")
+	}
+}
+
+// pathFor derives the path h is served at from gvk, following the
+// controller-runtime builder convention, e.g. "/validate-apps-v1-deployment"
+// or "/mutate-core-v1-pod" for the core group.
+func pathFor(gvk schema.GroupVersionKind, h admission.Handler) (string, error) {
+	group := gvk.Group
+	if group == "" {
+		group = "core"
+	}
+	suffix := strings.ReplaceAll(group, ".", "-") + "-" + gvk.Version + "-" + strings.ToLower(gvk.Kind)
+
+	switch h.(type) {
+	case *ValidatingWebhook:
+		return "/validate-" + suffix, nil
+	case *MutatingWebhook:
+		return "/mutate-" + suffix, nil
+	default:
+		return "", fmt.Errorf("webhook: Register does not support %T, want *ValidatingWebhook or *MutatingWebhook", h)
+	}
+}
+
+// WebhookBuilder builds and registers the ValidatingWebhook and/or
+// MutatingWebhook for a single GroupVersionKind against a Server, resolving
+// the GVK from Server.Scheme the way controller-runtime's
+// builder.WebhookManagedBy does.
+type WebhookBuilder struct {
+	server *Server
+	obj    runtime.Object
+
+	validator          Validator
+	mutators           []Mutator
+	reinvocationPolicy admissionregistrationv1.ReinvocationPolicyType
+}
+
+// For starts building the webhooks for obj's GroupVersionKind, as resolved
+// from s.Scheme once Complete is called.
+func (s *Server) For(obj runtime.Object) *WebhookBuilder {
+	return &WebhookBuilder{server: s, obj: obj}
+}
+
+// WithValidator registers a ValidatingWebhook backed by v.
+func (b *WebhookBuilder) WithValidator(v Validator) *WebhookBuilder {
+	b.validator = v
+	return b
+}
+
+// WithMutator registers a MutatingWebhook running mutators in order,
+// appending to any mutators already added via a previous WithMutator call.
+func (b *WebhookBuilder) WithMutator(mutators ...Mutator) *WebhookBuilder {
+	b.mutators = append(b.mutators, mutators...)
+	return b
+}
+
+// WithReinvocationPolicy sets the ReinvocationPolicy of the MutatingWebhook
+// built by WithMutator. It has no effect unless WithMutator is also called.
+func (b *WebhookBuilder) WithReinvocationPolicy(policy admissionregistrationv1.ReinvocationPolicyType) *WebhookBuilder {
+	b.reinvocationPolicy = policy
+	return b
+}
+
+// Complete resolves obj's GroupVersionKind in Server.Scheme and registers
+// the configured webhooks with Server.
+func (b *WebhookBuilder) Complete() error {
+	gvks, _, err := b.server.Scheme.ObjectKinds(b.obj)
+	if err != nil {
+		return fmt.Errorf("webhook: looking up GroupVersionKind for %T: %w", b.obj, err)
+	}
+	gvk := gvks[0]
+
+	if b.validator != nil {
+		if err := b.server.Register(gvk, &ValidatingWebhook{Validator: b.validator}); err != nil {
+			return err
+		}
+	}
+	if len(b.mutators) > 0 {
+		if err := b.server.Register(gvk, &MutatingWebhook{
+			Mutators:           b.mutators,
+			ReinvocationPolicy: b.reinvocationPolicy,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
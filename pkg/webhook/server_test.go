@@ -0,0 +1,120 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/prometheus/client_golang/prometheus"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var _ = Describe("Server", func() {
+	var (
+		scheme *runtime.Scheme
+		server *Server
+		pod    *corev1.Pod
+	)
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Ω(corev1.AddToScheme(scheme)).Should(Succeed())
+		server = &Server{Scheme: scheme, Registerer: prometheus.NewRegistry()}
+		pod = &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"}}
+	})
+
+	serve := func() admission.Response {
+		raw, err := json.Marshal(pod)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		ar := admissionv1.AdmissionReview{
+			TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+			Request: &admissionv1.AdmissionRequest{
+				UID:       "the-uid",
+				Operation: admissionv1.Create,
+				Object:    runtime.RawExtension{Raw: raw},
+			},
+		}
+		body, err := json.Marshal(ar)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		req := httptest.NewRequest("POST", "/validate-core-v1-pod", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+
+		var reply admissionv1.AdmissionReview
+		Ω(json.Unmarshal(w.Body.Bytes(), &reply)).Should(Succeed())
+		Ω(reply.Response.UID).Should(Equal(ar.Request.UID))
+		return admission.Response{AdmissionResponse: *reply.Response}
+	}
+
+	It("derives the path from the GVK and registers a ValidatingWebhook under it", func() {
+		Ω(server.Register(corev1.SchemeGroupVersion.WithKind("Pod"), &ValidatingWebhook{
+			Validator: validatorFunc{createFn: func(context.Context, runtime.Object) error { return nil }},
+		})).Should(Succeed())
+
+		Ω(serve().Allowed).Should(BeTrue())
+	})
+
+	It("rejects registering the same path twice", func() {
+		gvk := corev1.SchemeGroupVersion.WithKind("Pod")
+		Ω(server.Register(gvk, &ValidatingWebhook{})).Should(Succeed())
+		Ω(server.Register(gvk, &ValidatingWebhook{})).Should(HaveOccurred())
+	})
+
+	It("404s for a path nothing was registered under", func() {
+		req := httptest.NewRequest("POST", "/validate-core-v1-pod", bytes.NewReader(nil))
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		Ω(w.Code).Should(Equal(404))
+	})
+
+	Context("For/WithValidator/WithMutator/Complete", func() {
+		It("registers both a validating and a mutating path for the same GVK", func() {
+			err := server.For(&corev1.Pod{}).
+				WithValidator(validatorFunc{createFn: func(context.Context, runtime.Object) error { return nil }}).
+				WithMutator(mutatorFunc(func(context.Context, runtime.Object) error { return nil })).
+				Complete()
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(serve().Allowed).Should(BeTrue())
+		})
+	})
+
+	It("hosts a ConversionWebhook alongside admission webhooks, defaulting its Scheme to Server.Scheme", func() {
+		cw := &ConversionWebhook{}
+		Ω(server.RegisterConversion("/convert", cw)).Should(Succeed())
+		Ω(cw.Scheme).Should(BeIdenticalTo(scheme))
+
+		req := httptest.NewRequest("POST", "/convert", bytes.NewReader([]byte(`{}`)))
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		Ω(w.Code).Should(Equal(200))
+	})
+})
+
+// validatorFunc lets a plain function satisfy Validator for ValidateCreate,
+// allowing every other operation.
+type validatorFunc struct {
+	createFn func(ctx context.Context, obj runtime.Object) error
+}
+
+func (f validatorFunc) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	return f.createFn(ctx, obj)
+}
+
+func (validatorFunc) ValidateUpdate(ctx context.Context, oldObj, obj runtime.Object) error {
+	return nil
+}
+
+func (validatorFunc) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
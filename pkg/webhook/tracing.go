@@ -0,0 +1,10 @@
+package webhook
+
+import (
+	"go.opentelemetry.io/otel"
+)
+
+// tracer is the Tracer used for every span created by this package: the
+// decode and handle steps in handler, and the Validator/Mutator invocation in
+// ValidatingWebhook/MutatingWebhook.
+var tracer = otel.Tracer("github.com/warmchang/k8s-generic-webhook/pkg/webhook")
@@ -0,0 +1,166 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// spanNamed returns the first recorded span with the given name, so tests
+// can assert on a specific step of the webhook.decode/handle/validate/mutate
+// chain without depending on ordering.
+func spanNamed(spans tracetest.SpanStubs, name string) *tracetest.SpanStub {
+	for i := range spans {
+		if spans[i].Name == name {
+			return &spans[i]
+		}
+	}
+	return nil
+}
+
+func hasExceptionEvent(span *tracetest.SpanStub) bool {
+	for _, e := range span.Events {
+		if e.Name == "exception" {
+			return true
+		}
+	}
+	return false
+}
+
+// tracingExporter collects every span created by the package-level tracer
+// for the whole test binary: the otel global TracerProvider only ever
+// delegates to the first SDK provider installed (see
+// go.opentelemetry.io/otel/internal/global), so it is installed once here
+// rather than per-test.
+var tracingExporter = tracetest.NewInMemoryExporter()
+
+func init() {
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(tracingExporter)))
+}
+
+var _ = Describe("tracing", func() {
+	BeforeEach(func() {
+		tracingExporter.Reset()
+	})
+
+	It("names the decode and handle spans created by handler", func() {
+		decoder, err := admission.NewDecoder(runtime.NewScheme())
+		Ω(err).ShouldNot(HaveOccurred())
+
+		h := &handler{
+			Handler: &ValidatingWebhook{},
+			Object:  &corev1.Pod{},
+		}
+		Ω(h.InjectDecoder(decoder)).Should(Succeed())
+
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}
+		raw, err := json.Marshal(pod)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		h.Handle(context.TODO(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Object: runtime.RawExtension{Raw: raw},
+			},
+		})
+
+		spans := tracingExporter.GetSpans()
+		Ω(spanNamed(spans, "webhook.decode")).ShouldNot(BeNil())
+		Ω(spanNamed(spans, "webhook.handle")).ShouldNot(BeNil())
+	})
+
+	It("records the decode error as a span event when DecodeRaw fails", func() {
+		decoder, err := admission.NewDecoder(runtime.NewScheme())
+		Ω(err).ShouldNot(HaveOccurred())
+
+		h := &handler{
+			Handler: &ValidatingWebhook{},
+			Object:  &corev1.Pod{},
+		}
+		Ω(h.InjectDecoder(decoder)).Should(Succeed())
+
+		h.Handle(context.TODO(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Object: runtime.RawExtension{Raw: []byte("not json")},
+			},
+		})
+
+		span := spanNamed(tracingExporter.GetSpans(), "webhook.decode")
+		Ω(span).ShouldNot(BeNil())
+		Ω(hasExceptionEvent(span)).Should(BeTrue())
+	})
+
+	It("names the validate span and records the Validator's error", func() {
+		w := &ValidatingWebhook{Validator: &trackingValidator{err: fmt.Errorf("nope")}}
+		pod := &corev1.Pod{}
+
+		w.Handle(context.TODO(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Object:    runtime.RawExtension{Object: pod},
+			},
+		})
+
+		span := spanNamed(tracingExporter.GetSpans(), "webhook.validate")
+		Ω(span).ShouldNot(BeNil())
+		Ω(hasExceptionEvent(span)).Should(BeTrue())
+	})
+
+	It("names the mutate span and records a Mutator's error", func() {
+		pod := &corev1.Pod{}
+		failing := mutatorFunc(func(context.Context, runtime.Object) error {
+			return fmt.Errorf("nope")
+		})
+		w := &MutatingWebhook{Mutators: []Mutator{failing}}
+
+		w.Handle(context.TODO(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Object: runtime.RawExtension{Object: pod},
+			},
+		})
+
+		span := spanNamed(tracingExporter.GetSpans(), "webhook.mutate")
+		Ω(span).ShouldNot(BeNil())
+		Ω(hasExceptionEvent(span)).Should(BeTrue())
+	})
+
+	It("records the non-convergence error on the mutate span", func() {
+		pod := &corev1.Pod{}
+		flipFlop := mutatorFunc(func(ctx context.Context, obj runtime.Object) error {
+			p := obj.(*corev1.Pod)
+			if p.Labels["flip"] == "true" {
+				p.Labels = map[string]string{"flip": "false"}
+			} else {
+				p.Labels = map[string]string{"flip": "true"}
+			}
+			return nil
+		})
+		w := &MutatingWebhook{
+			Mutators:           []Mutator{flipFlop},
+			ReinvocationPolicy: admissionregistrationv1.IfNeededReinvocationPolicy,
+		}
+
+		w.Handle(context.TODO(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Object: runtime.RawExtension{Object: pod},
+			},
+		})
+
+		span := spanNamed(tracingExporter.GetSpans(), "webhook.mutate")
+		Ω(span).ShouldNot(BeNil())
+		Ω(hasExceptionEvent(span)).Should(BeTrue())
+	})
+})
@@ -0,0 +1,43 @@
+package webhook
+
+import (
+	"context"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// ValidateFuncs is an admission.Handler that dispatches to a separate
+// function per CRUD operation, so callers don't have to switch on
+// request.Operation themselves. Operations without a configured func are
+// allowed. Each func can retrieve a logger pre-populated with request
+// metadata via logf.FromContext(ctx).
+type ValidateFuncs struct {
+	CreateFunc  func(ctx context.Context, req admission.Request) admission.Response
+	UpdateFunc  func(ctx context.Context, req admission.Request) admission.Response
+	DeleteFunc  func(ctx context.Context, req admission.Request) admission.Response
+	ConnectFunc func(ctx context.Context, req admission.Request) admission.Response
+}
+
+// Handle implements admission.Handler.
+func (f *ValidateFuncs) Handle(ctx context.Context, req admission.Request) admission.Response {
+	switch req.Operation {
+	case admissionv1.Create:
+		if f.CreateFunc != nil {
+			return f.CreateFunc(ctx, req)
+		}
+	case admissionv1.Update:
+		if f.UpdateFunc != nil {
+			return f.UpdateFunc(ctx, req)
+		}
+	case admissionv1.Delete:
+		if f.DeleteFunc != nil {
+			return f.DeleteFunc(ctx, req)
+		}
+	case admissionv1.Connect:
+		if f.ConnectFunc != nil {
+			return f.ConnectFunc(ctx, req)
+		}
+	}
+	return admission.Allowed("")
+}
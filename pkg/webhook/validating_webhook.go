@@ -0,0 +1,100 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// Validator validates an object independently of that object's Go type
+// implementing any particular interface, so the same Validator can be reused
+// across unrelated types.
+type Validator interface {
+	ValidateCreate(ctx context.Context, obj runtime.Object) error
+	ValidateUpdate(ctx context.Context, oldObj, obj runtime.Object) error
+	ValidateDelete(ctx context.Context, obj runtime.Object) error
+}
+
+// ValidatingWebhook is an admission.Handler that runs a Validator against the
+// decoded object carried by the request.
+type ValidatingWebhook struct {
+	// Validator is invoked with the decoded Object/OldObject for the
+	// operation in question. A nil Validator allows every request.
+	Validator Validator
+
+	// Decoder and Client are populated via InjectDecoder/InjectClient and
+	// made available to Validator implementations that need them.
+	Decoder *admission.Decoder
+	Client  client.Client
+
+	// LogConstructor, if set, further enriches the logger already present
+	// in ctx (see handler.LogConstructor) before Validator is invoked.
+	LogConstructor func(base logr.Logger, req admission.Request) logr.Logger
+
+	// Observer, if set, is notified once Handle returns with the request's
+	// GVK/operation/decision and the time spent in Validator. Defaults to a
+	// no-op. Set this when ValidatingWebhook is used directly as an
+	// admission.Handler, without going through handler.
+	Observer Observer
+}
+
+// Handle implements admission.Handler.
+func (w *ValidatingWebhook) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if w.LogConstructor != nil {
+		ctx = logf.IntoContext(ctx, w.LogConstructor(logf.FromContext(ctx), req))
+	}
+
+	observer := w.Observer
+	if observer == nil {
+		observer = defaultObserver
+	}
+
+	start := time.Now()
+	resp := w.validate(ctx, req)
+	observer.ObserveRequest(req.Kind, req.Resource, req.Operation, resp.Allowed, time.Since(start))
+	return resp
+}
+
+func (w *ValidatingWebhook) validate(ctx context.Context, req admission.Request) admission.Response {
+	if w.Validator == nil {
+		return admission.Allowed("")
+	}
+
+	ctx, span := tracer.Start(ctx, "webhook.validate")
+	defer span.End()
+
+	var err error
+	switch req.Operation {
+	case admissionv1.Create:
+		err = w.Validator.ValidateCreate(ctx, req.Object.Object)
+	case admissionv1.Update:
+		err = w.Validator.ValidateUpdate(ctx, req.OldObject.Object, req.Object.Object)
+	case admissionv1.Delete:
+		err = w.Validator.ValidateDelete(ctx, req.OldObject.Object)
+	default:
+		return admission.Allowed("")
+	}
+	if err != nil {
+		span.RecordError(err)
+		return admission.Denied(err.Error())
+	}
+	return admission.Allowed("")
+}
+
+// InjectDecoder implements admission.DecoderInjector.
+func (w *ValidatingWebhook) InjectDecoder(d *admission.Decoder) error {
+	w.Decoder = d
+	return nil
+}
+
+// InjectClient implements inject.Client.
+func (w *ValidatingWebhook) InjectClient(c client.Client) error {
+	w.Client = c
+	return nil
+}
@@ -0,0 +1,126 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// trackingValidator records which ValidateX method was last invoked, and on
+// which object(s), so dispatch can be asserted without a fuller fake.
+type trackingValidator struct {
+	called string
+	err    error
+}
+
+func (v *trackingValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	v.called = "create"
+	return v.err
+}
+
+func (v *trackingValidator) ValidateUpdate(ctx context.Context, oldObj, obj runtime.Object) error {
+	v.called = "update"
+	return v.err
+}
+
+func (v *trackingValidator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	v.called = "delete"
+	return v.err
+}
+
+// recordingObserver records every ObserveRequest call it receives.
+type recordingObserver struct {
+	calls []struct {
+		operation admissionv1.Operation
+		allowed   bool
+	}
+}
+
+func (o *recordingObserver) ObserveRequest(_ metav1.GroupVersionKind, _ metav1.GroupVersionResource, operation admissionv1.Operation, allowed bool, _ time.Duration) {
+	o.calls = append(o.calls, struct {
+		operation admissionv1.Operation
+		allowed   bool
+	}{operation, allowed})
+}
+
+var _ = Describe("ValidatingWebhook", func() {
+	var pod *corev1.Pod
+
+	BeforeEach(func() {
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "bar",
+			},
+		}
+	})
+
+	handle := func(w *ValidatingWebhook, op admissionv1.Operation) admission.Response {
+		return w.Handle(context.TODO(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: op,
+				Object:    runtime.RawExtension{Object: pod},
+				OldObject: runtime.RawExtension{Object: pod},
+			},
+		})
+	}
+
+	It("allows every request when Validator is nil", func() {
+		result := handle(&ValidatingWebhook{}, admissionv1.Create)
+		Ω(result.Allowed).Should(BeTrue())
+	})
+
+	It("allows the request when the operation isn't Create, Update, or Delete", func() {
+		validator := &trackingValidator{err: fmt.Errorf("should not be called")}
+		result := handle(&ValidatingWebhook{Validator: validator}, admissionv1.Connect)
+		Ω(result.Allowed).Should(BeTrue())
+		Ω(validator.called).Should(BeEmpty())
+	})
+
+	It("dispatches Create to ValidateCreate", func() {
+		validator := &trackingValidator{}
+		result := handle(&ValidatingWebhook{Validator: validator}, admissionv1.Create)
+		Ω(result.Allowed).Should(BeTrue())
+		Ω(validator.called).Should(Equal("create"))
+	})
+
+	It("dispatches Update to ValidateUpdate", func() {
+		validator := &trackingValidator{}
+		result := handle(&ValidatingWebhook{Validator: validator}, admissionv1.Update)
+		Ω(result.Allowed).Should(BeTrue())
+		Ω(validator.called).Should(Equal("update"))
+	})
+
+	It("dispatches Delete to ValidateDelete", func() {
+		validator := &trackingValidator{}
+		result := handle(&ValidatingWebhook{Validator: validator}, admissionv1.Delete)
+		Ω(result.Allowed).Should(BeTrue())
+		Ω(validator.called).Should(Equal("delete"))
+	})
+
+	It("denies the request with the Validator's error message", func() {
+		validator := &trackingValidator{err: fmt.Errorf("nope")}
+		result := handle(&ValidatingWebhook{Validator: validator}, admissionv1.Create)
+		Ω(result.Allowed).Should(BeFalse())
+		Ω(string(result.Result.Reason)).Should(Equal("nope"))
+	})
+
+	It("notifies Observer with the operation and decision once Handle returns", func() {
+		observer := &recordingObserver{}
+		validator := &trackingValidator{err: fmt.Errorf("nope")}
+		handle(&ValidatingWebhook{Validator: validator, Observer: observer}, admissionv1.Create)
+
+		Ω(observer.calls).Should(HaveLen(1))
+		Ω(observer.calls[0].operation).Should(Equal(admissionv1.Create))
+		Ω(observer.calls[0].allowed).Should(BeFalse())
+	})
+})
@@ -0,0 +1,76 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// Scheme and Codecs know how to decode both admission/v1 and
+// admission/v1beta1 AdmissionReview payloads, so that a webhook registered
+// against this package can be exposed to clusters still sending the
+// deprecated v1beta1 review.
+var (
+	Scheme = runtime.NewScheme()
+	Codecs = serializer.NewCodecFactory(Scheme)
+)
+
+func init() {
+	utilruntime.Must(admissionv1.AddToScheme(Scheme))
+	utilruntime.Must(admissionv1beta1.AddToScheme(Scheme))
+}
+
+// unversionedAdmissionReview decodes into an admissionv1.AdmissionReview
+// regardless of whether the payload on the wire is admission/v1 or
+// admission/v1beta1: the two types are wire-compatible field for field, so
+// the v1beta1 bytes unmarshal cleanly into the v1 Go type. Codecs reports
+// which version was actually sent via the GroupVersionKind it returns, so
+// EncodeAdmissionReview can reply in kind.
+type unversionedAdmissionReview struct {
+	admissionv1.AdmissionReview
+}
+
+var _ runtime.Object = &unversionedAdmissionReview{}
+
+// DecodeAdmissionReview decodes body as either an admission/v1 or
+// admission/v1beta1 AdmissionReview and returns the embedded request as a
+// version-agnostic admission.Request, along with the GroupVersionKind the
+// AdmissionReview was actually sent as so the response can be encoded the
+// same way.
+func DecodeAdmissionReview(body []byte) (admission.Request, schema.GroupVersionKind, error) {
+	req := admission.Request{}
+	ar := unversionedAdmissionReview{}
+	// Avoid a copy: point the unversioned review's Request straight at req.
+	ar.Request = &req.AdmissionRequest
+	ar.SetGroupVersionKind(admissionv1.SchemeGroupVersion.WithKind("AdmissionReview"))
+
+	_, actualGVK, err := Codecs.UniversalDeserializer().Decode(body, nil, &ar)
+	if err != nil {
+		return admission.Request{}, schema.GroupVersionKind{}, err
+	}
+	if actualGVK == nil {
+		gvk := admissionv1.SchemeGroupVersion.WithKind("AdmissionReview")
+		actualGVK = &gvk
+	}
+	return req, *actualGVK, nil
+}
+
+// EncodeAdmissionReview writes resp to w as an AdmissionReview of the given
+// GroupVersionKind. gvk is normally the one returned by
+// DecodeAdmissionReview, so the reply matches the version the request came
+// in as; the zero value falls back to admission/v1.
+func EncodeAdmissionReview(w io.Writer, resp admission.Response, gvk schema.GroupVersionKind) error {
+	ar := admissionv1.AdmissionReview{Response: &resp.AdmissionResponse}
+	if gvk == (schema.GroupVersionKind{}) {
+		gvk = admissionv1.SchemeGroupVersion.WithKind("AdmissionReview")
+	}
+	ar.SetGroupVersionKind(gvk)
+	return json.NewEncoder(w).Encode(ar)
+}
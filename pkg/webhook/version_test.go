@@ -0,0 +1,88 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var _ = Describe("DecodeAdmissionReview/EncodeAdmissionReview", func() {
+	It("round-trips an admission/v1 AdmissionReview", func() {
+		review := admissionv1.AdmissionReview{
+			Request: &admissionv1.AdmissionRequest{
+				UID:       "v1-uid",
+				Operation: admissionv1.Create,
+			},
+		}
+		review.SetGroupVersionKind(admissionv1.SchemeGroupVersion.WithKind("AdmissionReview"))
+		body, err := json.Marshal(review)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		req, gvk, err := DecodeAdmissionReview(body)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(req.UID).Should(Equal(review.Request.UID))
+		Ω(gvk).Should(Equal(admissionv1.SchemeGroupVersion.WithKind("AdmissionReview")))
+
+		var buf bytes.Buffer
+		Ω(EncodeAdmissionReview(&buf, admission.Allowed(""), gvk)).Should(Succeed())
+
+		var out admissionv1.AdmissionReview
+		Ω(json.Unmarshal(buf.Bytes(), &out)).Should(Succeed())
+		Ω(out.APIVersion).Should(Equal(admissionv1.SchemeGroupVersion.String()))
+		Ω(out.Response.Allowed).Should(BeTrue())
+	})
+
+	It("round-trips an admission/v1beta1 AdmissionReview", func() {
+		review := admissionv1beta1.AdmissionReview{
+			Request: &admissionv1beta1.AdmissionRequest{
+				UID:       "v1beta1-uid",
+				Operation: admissionv1beta1.Update,
+			},
+		}
+		review.SetGroupVersionKind(admissionv1beta1.SchemeGroupVersion.WithKind("AdmissionReview"))
+		body, err := json.Marshal(review)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		req, gvk, err := DecodeAdmissionReview(body)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(req.UID).Should(Equal(review.Request.UID))
+		Ω(req.Operation).Should(Equal(admissionv1.Update))
+		Ω(gvk).Should(Equal(admissionv1beta1.SchemeGroupVersion.WithKind("AdmissionReview")))
+
+		var buf bytes.Buffer
+		Ω(EncodeAdmissionReview(&buf, admission.Denied("no"), gvk)).Should(Succeed())
+
+		var out admissionv1beta1.AdmissionReview
+		Ω(json.Unmarshal(buf.Bytes(), &out)).Should(Succeed())
+		Ω(out.APIVersion).Should(Equal(admissionv1beta1.SchemeGroupVersion.String()))
+		Ω(out.Response.Allowed).Should(BeFalse())
+	})
+
+	It("handles a mixed-version cluster sending both versions to the same webhook", func() {
+		reviewA := admissionv1.AdmissionReview{Request: &admissionv1.AdmissionRequest{UID: "cluster-a"}}
+		reviewA.SetGroupVersionKind(admissionv1.SchemeGroupVersion.WithKind("AdmissionReview"))
+		v1Body, err := json.Marshal(reviewA)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		reviewB := admissionv1beta1.AdmissionReview{Request: &admissionv1beta1.AdmissionRequest{UID: "cluster-b"}}
+		reviewB.SetGroupVersionKind(admissionv1beta1.SchemeGroupVersion.WithKind("AdmissionReview"))
+		v1beta1Body, err := json.Marshal(reviewB)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		reqA, gvkA, err := DecodeAdmissionReview(v1Body)
+		Ω(err).ShouldNot(HaveOccurred())
+		reqB, gvkB, err := DecodeAdmissionReview(v1beta1Body)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(string(reqA.UID)).Should(Equal("cluster-a"))
+		Ω(string(reqB.UID)).Should(Equal("cluster-b"))
+		Ω(gvkA.Version).Should(Equal("v1"))
+		Ω(gvkB.Version).Should(Equal("v1beta1"))
+	})
+})
@@ -0,0 +1,79 @@
+package webhook
+
+import (
+	"github.com/go-logr/logr"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// WebhookOptions carries the optional extras that ValidatingWebhook and
+// MutatingWebhook expose (see their Observer/LogConstructor fields) through
+// to the New* constructors below, for callers that don't go through Server.
+// A nil *WebhookOptions is equivalent to a zero-value one.
+type WebhookOptions struct {
+	// Observer, if set, is notified once Handle returns with the request's
+	// GVK/operation/decision and latency. Defaults to a no-op.
+	Observer Observer
+
+	// LogConstructor, if set, further enriches the logger already present
+	// in ctx (see handler.LogConstructor) before the Validator/Mutator chain
+	// is invoked.
+	LogConstructor func(base logr.Logger, req admission.Request) logr.Logger
+}
+
+func (o *WebhookOptions) observer() Observer {
+	if o == nil {
+		return nil
+	}
+	return o.Observer
+}
+
+func (o *WebhookOptions) logConstructor() func(base logr.Logger, req admission.Request) logr.Logger {
+	if o == nil {
+		return nil
+	}
+	return o.LogConstructor
+}
+
+// NewValidatingWebhook returns an admission.Webhook that decodes incoming
+// requests into obj and validates them with validator. opts may be nil.
+func NewValidatingWebhook(obj runtime.Object, validator Validator, opts *WebhookOptions) *admission.Webhook {
+	return &admission.Webhook{
+		Handler: &handler{
+			Handler: &ValidatingWebhook{
+				Validator:      validator,
+				Observer:       opts.observer(),
+				LogConstructor: opts.logConstructor(),
+			},
+			Object: obj,
+		},
+	}
+}
+
+// NewMutatingWebhook returns an admission.Webhook that decodes incoming
+// requests into obj and runs them through mutators in order. The chain is
+// run once per request; use NewMutatingWebhookWithReinvocation to re-run it
+// until it converges. opts may be nil.
+func NewMutatingWebhook(obj runtime.Object, opts *WebhookOptions, mutators ...Mutator) *admission.Webhook {
+	return NewMutatingWebhookWithReinvocation(obj, admissionregistrationv1.NeverReinvocationPolicy, opts, mutators...)
+}
+
+// NewMutatingWebhookWithReinvocation returns an admission.Webhook like
+// NewMutatingWebhook, but re-runs the mutator chain under policy (see
+// MutatingWebhook.ReinvocationPolicy) so that idempotent mutators can
+// observe each other's changes within a single admission evaluation. opts
+// may be nil.
+func NewMutatingWebhookWithReinvocation(obj runtime.Object, policy admissionregistrationv1.ReinvocationPolicyType, opts *WebhookOptions, mutators ...Mutator) *admission.Webhook {
+	return &admission.Webhook{
+		Handler: &handler{
+			Handler: &MutatingWebhook{
+				Mutators:           mutators,
+				ReinvocationPolicy: policy,
+				Observer:           opts.observer(),
+				LogConstructor:     opts.logConstructor(),
+			},
+			Object: obj,
+		},
+	}
+}
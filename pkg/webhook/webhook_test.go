@@ -0,0 +1,60 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var _ = Describe("NewValidatingWebhook/NewMutatingWebhook", func() {
+	var pod *corev1.Pod
+	var raw []byte
+
+	BeforeEach(func() {
+		pod = &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"}}
+		var err error
+		raw, err = json.Marshal(pod)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("reaches the Observer passed via WebhookOptions for a validating webhook", func() {
+		observer := &recordingObserver{}
+		w := NewValidatingWebhook(&corev1.Pod{}, &trackingValidator{}, &WebhookOptions{Observer: observer})
+
+		w.Handler.Handle(context.TODO(), admissionRequest(admissionv1.Create, raw))
+
+		Ω(observer.calls).Should(HaveLen(1))
+	})
+
+	It("reaches the Observer passed via WebhookOptions for a mutating webhook", func() {
+		observer := &recordingObserver{}
+		w := NewMutatingWebhook(&corev1.Pod{}, &WebhookOptions{Observer: observer}, mutatorFunc(func(context.Context, runtime.Object) error { return nil }))
+
+		w.Handler.Handle(context.TODO(), admissionRequest(admissionv1.Create, raw))
+
+		Ω(observer.calls).Should(HaveLen(1))
+	})
+
+	It("treats a nil WebhookOptions like a zero value", func() {
+		w := NewValidatingWebhook(&corev1.Pod{}, &trackingValidator{}, nil)
+		result := w.Handler.Handle(context.TODO(), admissionRequest(admissionv1.Create, raw))
+		Ω(result.Allowed).Should(BeTrue())
+	})
+})
+
+func admissionRequest(op admissionv1.Operation, raw []byte) admission.Request {
+	return admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: op,
+			Object:    runtime.RawExtension{Raw: raw},
+		},
+	}
+}